@@ -16,11 +16,12 @@ import (
 	"log"
 	"os"
 	"os/exec"
-	"strconv"
 	"strings"
 	"unicode/utf8"
 
 	"9fans.net/go/acme"
+
+	"github.com/qeedquan/acme/internal/diff"
 )
 
 var (
@@ -28,6 +29,9 @@ var (
 	fmtcmd  = flag.String("c", "", "custom formatter")
 	fmtexts = flag.String("e", "", "custom extensions")
 
+	diffengine = flag.String("diff-engine", "myers", "diff engine used to compute changes: myers or rewrite")
+	diffmode   = flag.String("diff-mode", "none", "diff format printed to stderr when applying changes: none, ed, unified, or json")
+
 	cexts  = []string{".c", ".cc", ".cpp", ".cxx", ".h", ".hpp"}
 	goexts = []string{".go"}
 )
@@ -113,20 +117,22 @@ func reformat(id int, name, cmd string) {
 		return
 	}
 
-	f, err := ioutil.TempFile("", "acmefmt")
+	d, err := diff.Get(*diffengine)
 	if err != nil {
 		log.Print(err)
 		return
 	}
-	if _, err := f.Write(new); err != nil {
+	oldLines := diff.SplitLines(old)
+	newLines := diff.SplitLines(new)
+	hunks, err := d.Diff(oldLines, newLines)
+	if err != nil {
 		log.Print(err)
 		return
 	}
-	tmp := f.Name()
-	f.Close()
-	defer os.Remove(tmp)
-
-	diff, _ := exec.Command("9", "diff", name, tmp).CombinedOutput()
+	if len(hunks) == 0 {
+		return
+	}
+	printDiff(hunks, oldLines, newLines, name)
 
 	latest, err := w.ReadAll("body")
 	if err != nil {
@@ -140,45 +146,25 @@ func reformat(id int, name, cmd string) {
 
 	w.Write("ctl", []byte("mark"))
 	w.Write("ctl", []byte("nomark"))
-	diffLines := strings.Split(string(diff), "\n")
-	for i := len(diffLines) - 1; i >= 0; i-- {
-		line := diffLines[i]
-		if line == "" {
-			continue
-		}
-		if line[0] == '<' || line[0] == '-' || line[0] == '>' {
-			continue
-		}
-		j := 0
-		for j < len(line) && line[j] != 'a' && line[j] != 'c' && line[j] != 'd' {
-			j++
-		}
-		if j >= len(line) {
-			log.Printf("cannot parse diff line: %q", line)
-			break
-		}
-		oldStart, oldEnd := parseSpan(line[:j])
-		newStart, newEnd := parseSpan(line[j+1:])
-		if oldStart == 0 || newStart == 0 {
-			continue
-		}
-		switch line[j] {
-		case 'a':
-			err := w.Addr("%d+#0", oldStart)
+	for i := len(hunks) - 1; i >= 0; i-- {
+		h := hunks[i]
+		switch h.Op {
+		case diff.Add:
+			err := w.Addr("%d+#0", h.OldStart)
 			if err != nil {
 				log.Print(err)
 				break
 			}
-			w.Write("data", findLines(new, newStart, newEnd))
-		case 'c':
-			err := w.Addr("%d,%d", oldStart, oldEnd)
+			w.Write("data", joinLines(newLines, h.NewStart, h.NewEnd))
+		case diff.Change:
+			err := w.Addr("%d,%d", h.OldStart, h.OldEnd)
 			if err != nil {
 				log.Print(err)
 				break
 			}
-			w.Write("data", findLines(new, newStart, newEnd))
-		case 'd':
-			err := w.Addr("%d,%d", oldStart, oldEnd)
+			w.Write("data", joinLines(newLines, h.NewStart, h.NewEnd))
+		case diff.Delete:
+			err := w.Addr("%d,%d", h.OldStart, h.OldEnd)
 			if err != nil {
 				log.Print(err)
 				break
@@ -188,41 +174,38 @@ func reformat(id int, name, cmd string) {
 	}
 }
 
-func parseSpan(text string) (start, end int) {
-	i := strings.Index(text, ",")
-	if i < 0 {
-		n, err := strconv.Atoi(text)
+// printDiff writes hunks to stderr in the format selected by
+// -diff-mode, for users who want to see what acmefmt is about to
+// apply before it lands in the window. It does nothing by default
+// (-diff-mode none), since acmefmt otherwise runs silently in the
+// background on every window Put.
+func printDiff(hunks []diff.Hunk, old, new [][]byte, name string) {
+	switch *diffmode {
+	case "none":
+		return
+	case "ed":
+		fmt.Fprintf(os.Stderr, "%s", diff.FormatEd(hunks, old, new))
+	case "unified":
+		fmt.Fprintf(os.Stderr, "%s", diff.FormatUnified(hunks, old, new, name, name))
+	case "json":
+		b, err := diff.FormatJSON(hunks, old, new)
 		if err != nil {
-			log.Printf("cannot parse span %q", text)
-			return 0, 0
+			log.Print(err)
+			return
 		}
-		return n, n
+		fmt.Fprintf(os.Stderr, "%s\n", b)
+	default:
+		log.Printf("unknown -diff-mode %q", *diffmode)
 	}
-	start, err1 := strconv.Atoi(text[:i])
-	end, err2 := strconv.Atoi(text[i+1:])
-	if err1 != nil || err2 != nil {
-		log.Printf("cannot parse span %q", text)
-		return 0, 0
-	}
-	return start, end
 }
 
-func findLines(text []byte, start, end int) []byte {
-	i := 0
-
-	start--
-	for ; i < len(text) && start > 0; i++ {
-		if text[i] == '\n' {
-			start--
-			end--
-		}
+func joinLines(lines [][]byte, start, end int) []byte {
+	if end < start {
+		return nil
 	}
-	startByte := i
-	for ; i < len(text) && end > 0; i++ {
-		if text[i] == '\n' {
-			end--
-		}
+	var buf bytes.Buffer
+	for i := start; i <= end; i++ {
+		buf.Write(lines[i-1])
 	}
-	endByte := i
-	return text[startByte:endByte]
+	return buf.Bytes()
 }
@@ -0,0 +1,72 @@
+// Package diff computes and renders line-based differences between
+// two texts without shelling out to an external diff(1) binary.
+//
+// A Differ turns two slices of lines into a sequence of Hunks; the
+// Format* functions turn those Hunks back into text, classic ed-style,
+// unified, or a JSON hunk stream. Differs register themselves by name
+// in an init function, following the database/sql driver pattern, so
+// callers can select an engine with a flag instead of a compile-time
+// import.
+package diff
+
+import "fmt"
+
+// Op identifies the kind of edit a Hunk represents, using the same
+// letters as ed(1) and the diff(1) script format.
+type Op byte
+
+const (
+	Add    Op = 'a' // lines NewStart..NewEnd were appended after OldStart
+	Change Op = 'c' // OldStart..OldEnd were replaced by NewStart..NewEnd
+	Delete Op = 'd' // OldStart..OldEnd were removed
+)
+
+func (op Op) String() string {
+	switch op {
+	case Add:
+		return "add"
+	case Change:
+		return "change"
+	case Delete:
+		return "delete"
+	default:
+		return fmt.Sprintf("Op(%d)", byte(op))
+	}
+}
+
+// Hunk is a single contiguous edit between two texts. OldStart/OldEnd
+// and NewStart/NewEnd are 1-indexed, inclusive line numbers; an empty
+// range is represented the way ed(1) does it, with End == Start-1.
+type Hunk struct {
+	Op               Op
+	OldStart, OldEnd int
+	NewStart, NewEnd int
+}
+
+// Differ computes the edits required to turn old into new, returning
+// the Hunks in increasing line order.
+type Differ interface {
+	Diff(old, new [][]byte) ([]Hunk, error)
+}
+
+// SplitLines splits text into lines, each retaining its trailing
+// newline (the last line keeps one only if text itself ends in one).
+// It produces the line slices that Differ.Diff and the Format
+// functions expect.
+func SplitLines(text []byte) [][]byte {
+	if len(text) == 0 {
+		return nil
+	}
+	var lines [][]byte
+	start := 0
+	for i, b := range text {
+		if b == '\n' {
+			lines = append(lines, text[start:i+1])
+			start = i + 1
+		}
+	}
+	if start < len(text) {
+		lines = append(lines, text[start:])
+	}
+	return lines
+}
@@ -0,0 +1,80 @@
+package diff
+
+import (
+	"bytes"
+	"testing"
+)
+
+// apply reconstructs new from old by applying hunks in reverse line
+// order, the same way cmd/acmefmt's reformat does against an acme
+// window body.
+func apply(old [][]byte, hunks []Hunk, new [][]byte) [][]byte {
+	out := append([][]byte(nil), old...)
+	for i := len(hunks) - 1; i >= 0; i-- {
+		h := hunks[i]
+		switch h.Op {
+		case Add:
+			tail := append([][]byte(nil), out[h.OldStart:]...)
+			out = append(append(out[:h.OldStart:h.OldStart], new[h.NewStart-1:h.NewEnd]...), tail...)
+		case Change:
+			tail := append([][]byte(nil), out[h.OldEnd:]...)
+			out = append(append(out[:h.OldStart-1:h.OldStart-1], new[h.NewStart-1:h.NewEnd]...), tail...)
+		case Delete:
+			out = append(out[:h.OldStart-1:h.OldStart-1], out[h.OldEnd:]...)
+		}
+	}
+	return out
+}
+
+func join(lines [][]byte) string {
+	var buf bytes.Buffer
+	for _, l := range lines {
+		buf.Write(l)
+	}
+	return buf.String()
+}
+
+func TestDiffersRoundTrip(t *testing.T) {
+	cases := []struct {
+		name     string
+		old, new string
+	}{
+		{"identical", "a\nb\nc\n", "a\nb\nc\n"},
+		{"change-middle", "a\nb\nc\n", "a\nx\nc\n"},
+		{"insert", "a\nb\nc\n", "a\nb\nz\nc\n"},
+		{"delete", "a\nb\nc\nd\n", "a\nd\n"},
+		{"empty-old", "", "a\nb\n"},
+		{"empty-new", "a\nb\n", ""},
+		{"both-empty", "", ""},
+		{"no-trailing-newline", "a\nb", "a\nc"},
+		{"multi-hunk", "line1\nline2\nline3\nline4\nline5\n", "line1\nlineX\nline3\nline4\nlineY\nline5\n"},
+		// Content that looks like a diff header in the new text must
+		// not confuse hunk application, since hunks are applied by
+		// line range, not by re-parsing the new text.
+		{"looks-like-diff-header", "a\nb\nc\n", "a\n2,3c2,3\nc\n"},
+	}
+
+	for name, d := range differs {
+		d := d
+		for _, c := range cases {
+			t.Run(name+"/"+c.name, func(t *testing.T) {
+				old := SplitLines([]byte(c.old))
+				new := SplitLines([]byte(c.new))
+				hunks, err := d.Diff(old, new)
+				if err != nil {
+					t.Fatalf("Diff: %v", err)
+				}
+				got := join(apply(old, hunks, new))
+				if got != c.new {
+					t.Errorf("apply(hunks) = %q, want %q (hunks=%+v)", got, c.new, hunks)
+				}
+			})
+		}
+	}
+}
+
+func TestGetUnknown(t *testing.T) {
+	if _, err := Get("nonexistent"); err == nil {
+		t.Error("Get(\"nonexistent\") = nil error, want error")
+	}
+}
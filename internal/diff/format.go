@@ -0,0 +1,128 @@
+package diff
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// FormatEd renders hunks the way diff(1) does with no options: an
+// ed(1) script with '<'/'>' context lines and a '---' separator
+// between the old and new halves of a change.
+func FormatEd(hunks []Hunk, old, new [][]byte) []byte {
+	var buf bytes.Buffer
+	for _, h := range hunks {
+		switch h.Op {
+		case Add:
+			fmt.Fprintf(&buf, "%da%s\n", h.OldStart, edSpan(h.NewStart, h.NewEnd))
+			writeEdLines(&buf, new, h.NewStart, h.NewEnd, "> ")
+		case Delete:
+			fmt.Fprintf(&buf, "%sd%d\n", edSpan(h.OldStart, h.OldEnd), h.NewStart)
+			writeEdLines(&buf, old, h.OldStart, h.OldEnd, "< ")
+		case Change:
+			fmt.Fprintf(&buf, "%sc%s\n", edSpan(h.OldStart, h.OldEnd), edSpan(h.NewStart, h.NewEnd))
+			writeEdLines(&buf, old, h.OldStart, h.OldEnd, "< ")
+			buf.WriteString("---\n")
+			writeEdLines(&buf, new, h.NewStart, h.NewEnd, "> ")
+		}
+	}
+	return buf.Bytes()
+}
+
+// edSpan renders a line range the way diff(1)/ed(1) do: a bare line
+// number when the range is a single line, "start,end" otherwise.
+func edSpan(start, end int) string {
+	if start == end {
+		return fmt.Sprintf("%d", start)
+	}
+	return fmt.Sprintf("%d,%d", start, end)
+}
+
+func writeEdLines(buf *bytes.Buffer, lines [][]byte, start, end int, prefix string) {
+	for i := start; i <= end; i++ {
+		buf.WriteString(prefix)
+		writeLine(buf, lines[i-1])
+	}
+}
+
+// FormatUnified renders hunks in the -u format understood by
+// patch(1), git, and most code review tools. oldName and newName
+// label the --- and +++ headers.
+func FormatUnified(hunks []Hunk, old, new [][]byte, oldName, newName string) []byte {
+	if len(hunks) == 0 {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "--- %s\n+++ %s\n", oldName, newName)
+	for _, h := range hunks {
+		oldStart, oldCount := unifiedRange(h.OldStart, h.OldEnd)
+		newStart, newCount := unifiedRange(h.NewStart, h.NewEnd)
+		fmt.Fprintf(&buf, "@@ -%d,%d +%d,%d @@\n", oldStart, oldCount, newStart, newCount)
+		for i := h.OldStart; i <= h.OldEnd; i++ {
+			buf.WriteString("-")
+			writeLine(&buf, old[i-1])
+		}
+		for i := h.NewStart; i <= h.NewEnd; i++ {
+			buf.WriteString("+")
+			writeLine(&buf, new[i-1])
+		}
+	}
+	return buf.Bytes()
+}
+
+func unifiedRange(start, end int) (int, int) {
+	if end < start {
+		return start, 0
+	}
+	return start, end - start + 1
+}
+
+func writeLine(buf *bytes.Buffer, line []byte) {
+	buf.Write(line)
+	if len(line) == 0 || line[len(line)-1] != '\n' {
+		buf.WriteByte('\n')
+	}
+}
+
+// jsonHunk is the wire format FormatJSON emits: one object per Hunk,
+// carrying the affected line ranges and their text so a consumer
+// never needs to re-parse ed or unified diff syntax.
+type jsonHunk struct {
+	Op       string   `json:"op"`
+	OldStart int      `json:"old_start"`
+	OldEnd   int      `json:"old_end"`
+	NewStart int      `json:"new_start"`
+	NewEnd   int      `json:"new_end"`
+	Old      []string `json:"old,omitempty"`
+	New      []string `json:"new,omitempty"`
+}
+
+// FormatJSON renders hunks as a machine-readable stream, suitable for
+// editors and tools that want to apply or inspect edits programmatically.
+func FormatJSON(hunks []Hunk, old, new [][]byte) ([]byte, error) {
+	out := make([]jsonHunk, len(hunks))
+	for i, h := range hunks {
+		out[i] = jsonHunk{
+			Op:       h.Op.String(),
+			OldStart: h.OldStart,
+			OldEnd:   h.OldEnd,
+			NewStart: h.NewStart,
+			NewEnd:   h.NewEnd,
+			Old:      linesToStrings(old, h.OldStart, h.OldEnd),
+			New:      linesToStrings(new, h.NewStart, h.NewEnd),
+		}
+	}
+	return json.MarshalIndent(out, "", "\t")
+}
+
+func linesToStrings(lines [][]byte, start, end int) []string {
+	if end < start {
+		return nil
+	}
+	out := make([]string, 0, end-start+1)
+	for i := start; i <= end; i++ {
+		out = append(out, string(lines[i-1]))
+	}
+	return out
+}
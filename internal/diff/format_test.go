@@ -0,0 +1,44 @@
+package diff
+
+import "testing"
+
+// TestFormatEmptyInputs exercises every Differ/Format* combination over
+// the edge cases that previously panicked: a newly created file (empty
+// old) and a fully emptied file (empty new), plus identical input as a
+// no-op baseline.
+func TestFormatEmptyInputs(t *testing.T) {
+	cases := []struct {
+		name     string
+		old, new string
+	}{
+		{"identical", "a\nb\n", "a\nb\n"},
+		{"empty-old", "", "a\nb\n"},
+		{"empty-new", "a\nb\n", ""},
+	}
+
+	for engine, d := range differs {
+		d := d
+		for _, c := range cases {
+			t.Run(engine+"/"+c.name, func(t *testing.T) {
+				old := SplitLines([]byte(c.old))
+				new := SplitLines([]byte(c.new))
+				hunks, err := d.Diff(old, new)
+				if err != nil {
+					t.Fatalf("Diff: %v", err)
+				}
+
+				defer func() {
+					if r := recover(); r != nil {
+						t.Fatalf("panicked: %v", r)
+					}
+				}()
+
+				_ = FormatEd(hunks, old, new)
+				_ = FormatUnified(hunks, old, new, "old", "new")
+				if _, err := FormatJSON(hunks, old, new); err != nil {
+					t.Errorf("FormatJSON: %v", err)
+				}
+			})
+		}
+	}
+}
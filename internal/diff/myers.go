@@ -0,0 +1,155 @@
+package diff
+
+import "bytes"
+
+func init() {
+	Register("myers", myersDiffer{})
+}
+
+// myersDiffer finds the shortest edit script between two texts using
+// the algorithm from Eugene Myers, "An O(ND) Difference Algorithm and
+// Its Variations" (1986), the same technique diff(1) and git use.
+type myersDiffer struct{}
+
+func (myersDiffer) Diff(old, new [][]byte) ([]Hunk, error) {
+	return groupHunks(editScript(old, new)), nil
+}
+
+type editKind int
+
+const (
+	editEqual editKind = iota
+	editDelete
+	editInsert
+)
+
+// editScript returns the shortest sequence of equal/delete/insert
+// operations that turns old into new.
+func editScript(old, new [][]byte) []editKind {
+	n, m := len(old), len(new)
+	max := n + m
+	if max == 0 {
+		return nil
+	}
+
+	offset := max
+	v := make([]int, 2*max+1)
+	var trace [][]int
+
+	for d := 0; d <= max; d++ {
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[k-1+offset] < v[k+1+offset]) {
+				x = v[k+1+offset]
+			} else {
+				x = v[k-1+offset] + 1
+			}
+			y := x - k
+			for x < n && y < m && bytes.Equal(old[x], new[y]) {
+				x++
+				y++
+			}
+			v[k+offset] = x
+			if x >= n && y >= m {
+				trace = append(trace, append([]int(nil), v...))
+				return backtrack(trace, n, m, offset)
+			}
+		}
+		trace = append(trace, append([]int(nil), v...))
+	}
+	// Unreachable: the edit distance is bounded by max.
+	return backtrack(trace, n, m, offset)
+}
+
+// backtrack walks the trace of v-arrays produced by editScript from
+// (n, m) back to (0, 0), recovering the edits in reverse and then
+// reversing them into forward order.
+func backtrack(trace [][]int, n, m, offset int) []editKind {
+	var script []editKind
+	x, y := n, m
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := trace[d]
+		k := x - y
+
+		var prevK int
+		if k == -d || (k != d && v[k-1+offset] < v[k+1+offset]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := v[prevK+offset]
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			script = append(script, editEqual)
+			x--
+			y--
+		}
+		if d > 0 {
+			if x == prevX {
+				script = append(script, editInsert)
+			} else {
+				script = append(script, editDelete)
+			}
+		}
+		x, y = prevX, prevY
+	}
+
+	for i, j := 0, len(script)-1; i < j; i, j = i+1, j-1 {
+		script[i], script[j] = script[j], script[i]
+	}
+	return script
+}
+
+// groupHunks collapses runs of consecutive delete/insert operations
+// into Hunks, the way ed(1) diff scripts group them, rather than
+// reporting every changed line as its own edit.
+func groupHunks(script []editKind) []Hunk {
+	var hunks []Hunk
+	oldPos, newPos := 0, 0
+	i := 0
+	for i < len(script) {
+		if script[i] == editEqual {
+			oldPos++
+			newPos++
+			i++
+			continue
+		}
+
+		oldAnchor, newAnchor := oldPos, newPos
+		var nDel, nIns int
+		for i < len(script) && script[i] != editEqual {
+			switch script[i] {
+			case editDelete:
+				nDel++
+			case editInsert:
+				nIns++
+			}
+			i++
+		}
+		oldPos += nDel
+		newPos += nIns
+		hunks = append(hunks, makeHunk(oldAnchor, newAnchor, nDel, nIns))
+	}
+	return hunks
+}
+
+func makeHunk(oldAnchor, newAnchor, nDel, nIns int) Hunk {
+	h := Hunk{
+		OldStart: oldAnchor + 1,
+		OldEnd:   oldAnchor + nDel,
+		NewStart: newAnchor + 1,
+		NewEnd:   newAnchor + nIns,
+	}
+	switch {
+	case nDel > 0 && nIns > 0:
+		h.Op = Change
+	case nDel > 0:
+		h.Op = Delete
+		h.NewStart, h.NewEnd = newAnchor, newAnchor-1
+	default:
+		h.Op = Add
+		h.OldStart, h.OldEnd = oldAnchor, oldAnchor-1
+	}
+	return h
+}
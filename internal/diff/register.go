@@ -0,0 +1,27 @@
+package diff
+
+import "fmt"
+
+var differs = make(map[string]Differ)
+
+// Register makes a Differ available under name so it can be looked up
+// with Get, e.g. from a command-line flag. It panics if called twice
+// with the same name.
+func Register(name string, d Differ) {
+	if d == nil {
+		panic("diff: Register differ is nil")
+	}
+	if _, dup := differs[name]; dup {
+		panic("diff: Register called twice for differ " + name)
+	}
+	differs[name] = d
+}
+
+// Get looks up a Differ previously registered with Register.
+func Get(name string) (Differ, error) {
+	d, ok := differs[name]
+	if !ok {
+		return nil, fmt.Errorf("diff: unknown differ %q", name)
+	}
+	return d, nil
+}
@@ -0,0 +1,25 @@
+package diff
+
+func init() {
+	Register("rewrite", rewriteDiffer{})
+}
+
+// rewriteDiffer treats the whole file as a single hunk instead of
+// computing a line-level diff, the way gofmt-style tools regenerate a
+// file wholesale rather than patch it in place. It is cheap and exact
+// but, unlike myersDiffer, gives up the minimal-edit property, so a
+// single-character change still replaces the entire window body.
+type rewriteDiffer struct{}
+
+func (rewriteDiffer) Diff(old, new [][]byte) ([]Hunk, error) {
+	switch {
+	case len(old) == 0 && len(new) == 0:
+		return nil, nil
+	case len(old) == 0:
+		return []Hunk{{Op: Add, OldStart: 0, OldEnd: -1, NewStart: 1, NewEnd: len(new)}}, nil
+	case len(new) == 0:
+		return []Hunk{{Op: Delete, OldStart: 1, OldEnd: len(old), NewStart: 0, NewEnd: -1}}, nil
+	default:
+		return []Hunk{{Op: Change, OldStart: 1, OldEnd: len(old), NewStart: 1, NewEnd: len(new)}}, nil
+	}
+}